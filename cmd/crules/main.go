@@ -0,0 +1,230 @@
+// Command crules syncs a shared set of rules ("main rules") out to every
+// registered project directory.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"crules/internal/backup"
+	"crules/internal/core"
+	"crules/internal/ui"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "init":
+		err = cmdInit()
+	case "sync":
+		err = cmdSync()
+	case "merge":
+		err = cmdMerge(os.Args[2:])
+	case "verify":
+		err = cmdVerify()
+	case "clean":
+		err = cmdClean()
+	case "sync-to":
+		err = cmdSyncTo(os.Args[2:])
+	case "snapshot":
+		err = cmdSnapshot(os.Args[2:])
+	default:
+		printUsage()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		ui.Error("%v", err)
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: crules <init|sync|merge|verify|clean|sync-to|snapshot> [flags]")
+	fmt.Println("       crules snapshot [label]")
+	fmt.Println("       crules snapshot list")
+	fmt.Println("       crules snapshot restore ID")
+}
+
+func cmdInit() error {
+	sm, err := core.NewSyncManager()
+	if err != nil {
+		return err
+	}
+	return sm.Init(context.Background())
+}
+
+func cmdSync() error {
+	sm, err := core.NewSyncManager()
+	if err != nil {
+		return err
+	}
+	return sm.Sync(context.Background())
+}
+
+func cmdMerge(args []string) error {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	failFast := fs.Bool("fail-fast", false, "cancel remaining project syncs on the first failure")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sm, err := core.NewSyncManager()
+	if err != nil {
+		return err
+	}
+
+	results, mergeErr := sm.Merge(context.Background(), *failFast)
+	displaySyncResults(results)
+	return mergeErr
+}
+
+func cmdVerify() error {
+	sm, err := core.NewSyncManager()
+	if err != nil {
+		return err
+	}
+
+	results, err := sm.Verify(context.Background())
+	if err != nil {
+		return err
+	}
+	displayVerifyResults(results)
+	return nil
+}
+
+func cmdClean() error {
+	sm, err := core.NewSyncManager()
+	if err != nil {
+		return err
+	}
+	removed, err := sm.Clean()
+	if err != nil {
+		return err
+	}
+	ui.Success("Removed %d stale project(s) from the registry", removed)
+	return nil
+}
+
+func cmdSyncTo(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: crules sync-to PROJECT REF")
+	}
+
+	sm, err := core.NewSyncManager()
+	if err != nil {
+		return err
+	}
+	if err := sm.SyncTo(context.Background(), args[0], args[1]); err != nil {
+		return err
+	}
+	ui.Success("Pinned %s to %s", args[0], args[1])
+	return nil
+}
+
+// cmdSnapshot dispatches the "snapshot" verb's sub-commands:
+//
+//	crules snapshot [label]     create a snapshot, labeled label if given
+//	crules snapshot list        list every snapshot, oldest first
+//	crules snapshot restore ID  restore the main rules directory to ID
+func cmdSnapshot(args []string) error {
+	sm, err := core.NewSyncManager()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		id, err := sm.SnapshotCreate("manual")
+		if err != nil {
+			return err
+		}
+		ui.Success("Created snapshot %s", id)
+		return nil
+	}
+
+	switch args[0] {
+	case "list":
+		snapshots, err := sm.Snapshots()
+		if err != nil {
+			return err
+		}
+		displaySnapshots(snapshots)
+		return nil
+	case "restore":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: crules snapshot restore ID")
+		}
+		if err := sm.SnapshotRestore(backup.SnapshotID(args[1])); err != nil {
+			return err
+		}
+		ui.Success("Restored snapshot %s", args[1])
+		return nil
+	default:
+		id, err := sm.SnapshotCreate(args[0])
+		if err != nil {
+			return err
+		}
+		ui.Success("Created snapshot %s", id)
+		return nil
+	}
+}
+
+// displaySnapshots renders the snapshot index as a table, oldest first,
+// matching backup.Manager.List's documented ordering.
+func displaySnapshots(snapshots []backup.Snapshot) {
+	headers := []string{"ID", "Label", "Created At", "Source SHA"}
+	rows := make([][]string, len(snapshots))
+	for i, s := range snapshots {
+		rows[i] = []string{string(s.ID), s.Label, s.CreatedAt.Format("2006-01-02 15:04:05"), s.SourceSHA}
+	}
+	ui.DisplayTable(headers, rows)
+}
+
+// displaySyncResults renders a Merge/syncToAll result set as a
+// green OK / red FAIL / yellow SKIP table, in the style of ui.DisplayFileTable
+// but with an explicit headers/rows shape since SyncResult doesn't fit
+// DisplayFileTable's single-column signature.
+func displaySyncResults(results []core.SyncResult) {
+	headers := []string{"Project", "Status", "Duration"}
+	rows := make([][]string, len(results))
+	for i, r := range results {
+		status := "OK"
+		if r.Err != nil {
+			status = "FAIL: " + r.Err.Error()
+		} else if r.Skipped {
+			status = "SKIP (pinned)"
+		}
+		rows[i] = []string{r.Project, status, r.Duration.String()}
+	}
+	ui.DisplayTable(headers, rows)
+}
+
+// displayVerifyResults renders a Verify result set, flagging genuine drift
+// in unpinned projects without treating a pinned project's expected
+// tracking of its own PinnedRef as an error.
+func displayVerifyResults(results []core.VerifyResult) {
+	headers := []string{"Project", "Status"}
+	rows := make([][]string, len(results))
+	for i, r := range results {
+		status := "OK"
+		switch {
+		case r.Err != nil:
+			status = "ERROR: " + r.Err.Error()
+		case r.Drifted && r.Pinned:
+			status = "DRIFTED (pinned ref moved)"
+		case r.Drifted:
+			status = "DRIFTED"
+		case r.Pinned:
+			status = "OK (pinned)"
+		}
+		rows[i] = []string{r.Project, status}
+	}
+	ui.DisplayTable(headers, rows)
+}
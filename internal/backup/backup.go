@@ -0,0 +1,324 @@
+// Package backup snapshots the main rules directory to tar+gzip archives so
+// a destructive Merge (or any other operation that overwrites mainPath) can
+// be undone.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"crules/internal/git"
+)
+
+// indexFileName holds the JSON index of every snapshot taken.
+const indexFileName = "index.json"
+
+// SnapshotID identifies a single snapshot.
+type SnapshotID string
+
+// Snapshot is one point-in-time tar+gzip capture of the main rules directory.
+type Snapshot struct {
+	ID        SnapshotID `json:"id"`
+	Label     string     `json:"label"`
+	CreatedAt time.Time  `json:"created_at"`
+	SourceSHA string     `json:"source_sha,omitempty"`
+	Archive   string     `json:"archive"`
+}
+
+// Manager creates, lists, restores, and prunes snapshots of sourcePath under
+// dir (conventionally appPaths.DataDir/snapshots).
+type Manager struct {
+	sourcePath string
+	dir        string
+	gitService git.GitService
+}
+
+// NewManager creates a Manager that snapshots sourcePath into
+// dataDir/snapshots. gitService may be nil, in which case snapshots are
+// recorded without a source commit SHA.
+func NewManager(sourcePath, dataDir string, gitService git.GitService) *Manager {
+	return &Manager{
+		sourcePath: sourcePath,
+		dir:        filepath.Join(dataDir, "snapshots"),
+		gitService: gitService,
+	}
+}
+
+// Create archives sourcePath and records it in the index under label.
+func (m *Manager) Create(label string) (SnapshotID, error) {
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create snapshots directory: %w", err)
+	}
+
+	id := SnapshotID(time.Now().UTC().Format("20060102T150405.000000000Z"))
+	archiveName := string(id) + ".tar.gz"
+	archivePath := filepath.Join(m.dir, archiveName)
+
+	if err := archiveDir(m.sourcePath, archivePath); err != nil {
+		return "", fmt.Errorf("failed to archive %s: %w", m.sourcePath, err)
+	}
+
+	sourceSHA := ""
+	if m.gitService != nil {
+		if sha, err := m.gitService.Id(context.Background(), m.sourcePath); err == nil {
+			sourceSHA = sha
+		}
+	}
+
+	index, err := m.loadIndex()
+	if err != nil {
+		return "", err
+	}
+	index = append(index, Snapshot{
+		ID:        id,
+		Label:     label,
+		CreatedAt: time.Now(),
+		SourceSHA: sourceSHA,
+		Archive:   archiveName,
+	})
+	if err := m.saveIndex(index); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// List returns every recorded snapshot, oldest first.
+func (m *Manager) List() ([]Snapshot, error) {
+	return m.loadIndex()
+}
+
+// Restore unpacks snapshot id over sourcePath. The archive is first unpacked
+// into a temporary directory; only once that succeeds is the current
+// sourcePath renamed aside, the unpacked copy renamed into place, and the
+// aside copy removed — so a failed restore leaves the prior contents intact.
+func (m *Manager) Restore(id SnapshotID) error {
+	index, err := m.loadIndex()
+	if err != nil {
+		return err
+	}
+
+	var snap *Snapshot
+	for i := range index {
+		if index[i].ID == id {
+			snap = &index[i]
+			break
+		}
+	}
+	if snap == nil {
+		return fmt.Errorf("snapshot not found: %s", id)
+	}
+
+	tmpPath := m.sourcePath + ".restore-tmp"
+	asidePath := m.sourcePath + ".restore-aside"
+	_ = os.RemoveAll(tmpPath)
+	_ = os.RemoveAll(asidePath)
+
+	if err := unarchiveDir(filepath.Join(m.dir, snap.Archive), tmpPath); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return fmt.Errorf("failed to unpack snapshot %s: %w", id, err)
+	}
+
+	if err := os.Rename(m.sourcePath, asidePath); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return fmt.Errorf("failed to move %s aside: %w", m.sourcePath, err)
+	}
+
+	if err := os.Rename(tmpPath, m.sourcePath); err != nil {
+		_ = os.Rename(asidePath, m.sourcePath)
+		return fmt.Errorf("failed to restore snapshot %s: %w", id, err)
+	}
+
+	_ = os.RemoveAll(asidePath)
+	return nil
+}
+
+// Prune keeps the most recent keep snapshots and deletes the rest along with
+// their archives.
+func (m *Manager) Prune(keep int) error {
+	index, err := m.loadIndex()
+	if err != nil {
+		return err
+	}
+
+	// Sort a copy newest-first to find what to drop, but persist the
+	// survivors in index's original oldest-first order, so List()'s
+	// documented ordering holds after a prune.
+	byRecency := append([]Snapshot(nil), index...)
+	sort.Slice(byRecency, func(i, j int) bool { return byRecency[i].CreatedAt.After(byRecency[j].CreatedAt) })
+
+	if keep < 0 {
+		keep = 0
+	}
+	if len(byRecency) <= keep {
+		return nil
+	}
+
+	dropped := make(map[SnapshotID]bool, len(byRecency)-keep)
+	for _, snap := range byRecency[keep:] {
+		dropped[snap.ID] = true
+		if err := os.Remove(filepath.Join(m.dir, snap.Archive)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove archive for snapshot %s: %w", snap.ID, err)
+		}
+	}
+
+	kept := make([]Snapshot, 0, keep)
+	for _, snap := range index {
+		if !dropped[snap.ID] {
+			kept = append(kept, snap)
+		}
+	}
+
+	return m.saveIndex(kept)
+}
+
+func (m *Manager) loadIndex() ([]Snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(m.dir, indexFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot index: %w", err)
+	}
+
+	var index []Snapshot
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot index: %w", err)
+	}
+	return index, nil
+}
+
+func (m *Manager) saveIndex(index []Snapshot) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(m.dir, indexFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot index: %w", err)
+	}
+	return nil
+}
+
+// archiveDir writes src as a tar+gzip archive at dest.
+func archiveDir(src, dest string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	defer gz.Close()
+
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relPath)
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// isWithinDir reports whether target is dir itself or a descendant of it.
+func isWithinDir(dir, target string) bool {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (!filepath.IsAbs(rel) && !strings.HasPrefix(rel, ".."))
+}
+
+// unarchiveDir unpacks a tar+gzip archive created by archiveDir into dest.
+func unarchiveDir(archivePath, dest string) error {
+	in, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, header.Name)
+		if !isWithinDir(dest, target) {
+			return fmt.Errorf("invalid archive entry escapes destination: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return err
+			}
+			f.Close()
+		}
+	}
+}
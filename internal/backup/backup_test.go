@@ -0,0 +1,102 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestManager(t *testing.T) (*Manager, string) {
+	t.Helper()
+	root := t.TempDir()
+	source := filepath.Join(root, "rules")
+	if err := os.MkdirAll(source, 0755); err != nil {
+		t.Fatalf("failed to create source dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(source, "a.md"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("failed to seed source file: %v", err)
+	}
+	return NewManager(source, root, nil), source
+}
+
+// TestPrune_PreservesListOrder guards against regressing the bug fixed in
+// ae4fad9: Prune must drop the oldest entries but persist survivors in
+// List's documented oldest-first order, not the newest-first order it sorts
+// internally to decide what to drop.
+func TestPrune_PreservesListOrder(t *testing.T) {
+	mgr, _ := newTestManager(t)
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	index := []Snapshot{
+		{ID: "snap-a", Label: "a", CreatedAt: base, Archive: "snap-a.tar.gz"},
+		{ID: "snap-b", Label: "b", CreatedAt: base.Add(1 * time.Hour), Archive: "snap-b.tar.gz"},
+		{ID: "snap-c", Label: "c", CreatedAt: base.Add(2 * time.Hour), Archive: "snap-c.tar.gz"},
+		{ID: "snap-d", Label: "d", CreatedAt: base.Add(3 * time.Hour), Archive: "snap-d.tar.gz"},
+	}
+	if err := os.MkdirAll(mgr.dir, 0755); err != nil {
+		t.Fatalf("failed to create snapshots dir: %v", err)
+	}
+	if err := mgr.saveIndex(index); err != nil {
+		t.Fatalf("saveIndex() error = %v", err)
+	}
+
+	if err := mgr.Prune(2); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+
+	got, err := mgr.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	want := []SnapshotID{"snap-c", "snap-d"}
+	if len(got) != len(want) {
+		t.Fatalf("List() = %v, want %d survivors %v", got, len(want), want)
+	}
+	for i, id := range want {
+		if got[i].ID != id {
+			t.Errorf("List()[%d].ID = %q, want %q (oldest-first order not preserved)", i, got[i].ID, id)
+		}
+	}
+}
+
+// TestRestore_FailureLeavesSourceIntact asserts that a Restore which fails
+// partway through (here, because the archive is corrupt) does not destroy
+// the existing sourcePath contents.
+func TestRestore_FailureLeavesSourceIntact(t *testing.T) {
+	mgr, source := newTestManager(t)
+
+	id, err := mgr.Create("before-corruption")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	index, err := mgr.loadIndex()
+	if err != nil {
+		t.Fatalf("loadIndex() error = %v", err)
+	}
+	if len(index) != 1 {
+		t.Fatalf("loadIndex() = %v, want 1 entry", index)
+	}
+	archivePath := filepath.Join(mgr.dir, index[0].Archive)
+	if err := os.WriteFile(archivePath, []byte("not a valid gzip archive"), 0644); err != nil {
+		t.Fatalf("failed to corrupt archive: %v", err)
+	}
+
+	if err := mgr.Restore(id); err == nil {
+		t.Fatal("Restore() error = nil, want failure on corrupt archive")
+	}
+
+	data, err := os.ReadFile(filepath.Join(source, "a.md"))
+	if err != nil {
+		t.Fatalf("source file missing after failed restore: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("source file contents = %q, want %q (failed restore must not touch source)", data, "hello")
+	}
+
+	if _, err := os.Stat(source + ".restore-aside"); !os.IsNotExist(err) {
+		t.Errorf("leftover aside directory after failed restore: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"crules/internal/utils"
+)
+
+// LockFileName is the name of the per-project lockfile recording which
+// main-rules commit was synced into that project's rules directory.
+const LockFileName = "crules.lock"
+
+// ProjectLock pins a project's rules directory to the main-rules commit and
+// branch it was synced from, so SyncManager.Verify can detect drift.
+type ProjectLock struct {
+	SHA      string    `json:"sha"`
+	Branch   string    `json:"branch"`
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+// recordLock writes a crules.lock file next to project's rules directory,
+// capturing main's current commit SHA and branch.
+func (sm *SyncManager) recordLock(ctx context.Context, project string) error {
+	sha, err := sm.gitService.Id(ctx, sm.mainPath)
+	if err != nil {
+		return fmt.Errorf("failed to read main commit: %w", err)
+	}
+
+	branch, err := sm.gitService.Branch(ctx, sm.mainPath)
+	if err != nil {
+		utils.Warn("Failed to read main branch | path=" + sm.mainPath + ", error=" + err.Error())
+		branch = ""
+	}
+
+	lock := ProjectLock{SHA: sha, Branch: branch, SyncedAt: time.Now()}
+
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lockfile: %w", err)
+	}
+
+	perm := os.FileMode(0644)
+	if sm.config != nil {
+		perm = sm.config.DirPermission
+	}
+	if err := os.WriteFile(filepath.Join(project, LockFileName), data, perm); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return nil
+}
+
+// readLock reads project's crules.lock file.
+func readLock(project string) (*ProjectLock, error) {
+	data, err := os.ReadFile(filepath.Join(project, LockFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	var lock ProjectLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile: %w", err)
+	}
+	return &lock, nil
+}
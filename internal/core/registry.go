@@ -0,0 +1,155 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"crules/internal/utils"
+)
+
+// CurrentRegistrySchemaVersion is the schema version written by this build.
+// Version 0 (implicit, no SchemaVersion field) stored Projects as a plain
+// []string; version 1 introduced ProjectEntry to carry a PinnedRef alongside
+// each project's path.
+const CurrentRegistrySchemaVersion = 1
+
+// ProjectEntry is a single registered project.
+type ProjectEntry struct {
+	Path string `json:"path"`
+	// PinnedRef, when set, is the main-rules ref this project was pinned to
+	// via SyncManager.SyncTo. Pinned projects are skipped by syncToAll so a
+	// broad Merge doesn't silently overwrite an intentional pin.
+	PinnedRef string `json:"pinned_ref,omitempty"`
+}
+
+// Registry tracks every project that has been synced from the main rules
+// location, along with the schema version the file was written with.
+type Registry struct {
+	SchemaVersion int            `json:"schema_version"`
+	Projects      []ProjectEntry `json:"projects"`
+
+	path   string
+	config *utils.Config
+}
+
+// legacyRegistry is the v0 on-disk shape: a bare list of project paths with
+// no SchemaVersion field at all.
+type legacyRegistry struct {
+	Projects []string `json:"projects"`
+}
+
+// LoadRegistry loads the registry from path, creating an empty v1 registry
+// if the file does not exist yet, and migrating a v0 registry in place.
+func LoadRegistry(path string, config *utils.Config) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Registry{SchemaVersion: CurrentRegistrySchemaVersion, path: path, config: config}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry: %w", err)
+	}
+
+	// Sniff the schema version from a tolerant shape before attempting the
+	// typed decode: a real v0 file stores Projects as a plain []string, which
+	// fails to unmarshal directly into []ProjectEntry.
+	var versionProbe struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &versionProbe); err != nil {
+		return nil, fmt.Errorf("failed to parse registry: %w", err)
+	}
+
+	var reg Registry
+	if versionProbe.SchemaVersion == 0 {
+		var legacy legacyRegistry
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return nil, fmt.Errorf("failed to parse v0 registry: %w", err)
+		}
+		reg.Projects = make([]ProjectEntry, len(legacy.Projects))
+		for i, p := range legacy.Projects {
+			reg.Projects[i] = ProjectEntry{Path: p}
+		}
+		reg.SchemaVersion = CurrentRegistrySchemaVersion
+		reg.path = path
+		reg.config = config
+		utils.Debug("Migrated registry from schema v0 | path=" + path)
+		if err := reg.save(); err != nil {
+			return nil, fmt.Errorf("failed to persist migrated registry: %w", err)
+		}
+		return &reg, nil
+	}
+
+	if err := json.Unmarshal(data, &reg); err != nil {
+		return nil, fmt.Errorf("failed to parse registry: %w", err)
+	}
+	reg.path = path
+	reg.config = config
+
+	return &reg, nil
+}
+
+// save writes the registry back to its on-disk path.
+func (r *Registry) save() error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal registry: %w", err)
+	}
+	perm := os.FileMode(0644)
+	if r.config != nil {
+		perm = r.config.DirPermission
+	}
+	if err := os.WriteFile(r.path, data, perm); err != nil {
+		return fmt.Errorf("failed to write registry: %w", err)
+	}
+	return nil
+}
+
+// AddProject registers a new project path, or is a no-op if already present.
+func (r *Registry) AddProject(path string) error {
+	for _, p := range r.Projects {
+		if p.Path == path {
+			return nil
+		}
+	}
+	r.Projects = append(r.Projects, ProjectEntry{Path: path})
+	return r.save()
+}
+
+// GetProjects returns every registered project entry.
+func (r *Registry) GetProjects() []ProjectEntry {
+	return r.Projects
+}
+
+// SetPinnedRef records the main-rules ref a project is pinned to and persists it.
+func (r *Registry) SetPinnedRef(path, ref string) error {
+	for i := range r.Projects {
+		if r.Projects[i].Path == path {
+			r.Projects[i].PinnedRef = ref
+			return r.save()
+		}
+	}
+	return fmt.Errorf("project not registered: %s", path)
+}
+
+// CleanProjects removes registered projects whose path no longer exists on
+// disk and returns how many were removed.
+func (r *Registry) CleanProjects() (int, error) {
+	kept := r.Projects[:0]
+	removed := 0
+	for _, p := range r.Projects {
+		if utils.DirExists(p.Path) {
+			kept = append(kept, p)
+		} else {
+			removed++
+		}
+	}
+	r.Projects = kept
+
+	if removed > 0 {
+		if err := r.save(); err != nil {
+			return 0, err
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadRegistry_MigratesV0(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	v0 := `{"projects": ["/projects/a", "/projects/b"]}`
+	if err := os.WriteFile(path, []byte(v0), 0644); err != nil {
+		t.Fatalf("failed to seed v0 registry: %v", err)
+	}
+
+	reg, err := LoadRegistry(path, nil)
+	if err != nil {
+		t.Fatalf("LoadRegistry() error = %v", err)
+	}
+
+	if reg.SchemaVersion != CurrentRegistrySchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", reg.SchemaVersion, CurrentRegistrySchemaVersion)
+	}
+	if len(reg.Projects) != 2 {
+		t.Fatalf("Projects = %v, want 2 entries", reg.Projects)
+	}
+	wantPaths := []string{"/projects/a", "/projects/b"}
+	for i, want := range wantPaths {
+		if reg.Projects[i].Path != want {
+			t.Errorf("Projects[%d].Path = %q, want %q", i, reg.Projects[i].Path, want)
+		}
+		if reg.Projects[i].PinnedRef != "" {
+			t.Errorf("Projects[%d].PinnedRef = %q, want empty for a migrated v0 project", i, reg.Projects[i].PinnedRef)
+		}
+	}
+
+	// The migration must be persisted, not just held in memory.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read back migrated registry: %v", err)
+	}
+	var onDisk Registry
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		t.Fatalf("migrated registry on disk is not valid v1 JSON: %v", err)
+	}
+	if onDisk.SchemaVersion != CurrentRegistrySchemaVersion {
+		t.Errorf("on-disk SchemaVersion = %d, want %d", onDisk.SchemaVersion, CurrentRegistrySchemaVersion)
+	}
+	if len(onDisk.Projects) != 2 {
+		t.Errorf("on-disk Projects = %v, want 2 entries", onDisk.Projects)
+	}
+}
+
+func TestLoadRegistry_LoadsV1Unchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	v1 := `{"schema_version": 1, "projects": [{"path": "/projects/a", "pinned_ref": "v1.0.0"}]}`
+	if err := os.WriteFile(path, []byte(v1), 0644); err != nil {
+		t.Fatalf("failed to seed v1 registry: %v", err)
+	}
+
+	reg, err := LoadRegistry(path, nil)
+	if err != nil {
+		t.Fatalf("LoadRegistry() error = %v", err)
+	}
+
+	if reg.SchemaVersion != 1 {
+		t.Errorf("SchemaVersion = %d, want 1", reg.SchemaVersion)
+	}
+	if len(reg.Projects) != 1 || reg.Projects[0].Path != "/projects/a" || reg.Projects[0].PinnedRef != "v1.0.0" {
+		t.Errorf("Projects = %+v, want [{/projects/a v1.0.0}]", reg.Projects)
+	}
+}
+
+func TestLoadRegistry_MissingFileReturnsEmptyV1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+
+	reg, err := LoadRegistry(path, nil)
+	if err != nil {
+		t.Fatalf("LoadRegistry() error = %v", err)
+	}
+	if reg.SchemaVersion != CurrentRegistrySchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", reg.SchemaVersion, CurrentRegistrySchemaVersion)
+	}
+	if len(reg.Projects) != 0 {
+		t.Errorf("Projects = %v, want none", reg.Projects)
+	}
+}
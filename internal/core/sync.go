@@ -1,21 +1,40 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
 
+	"golang.org/x/sync/errgroup"
+
+	"crules/internal/backup"
 	"crules/internal/git"
 	"crules/internal/ui"
 	"crules/internal/utils"
 )
 
+// SyncResult records the outcome of syncing main rules to a single project.
+type SyncResult struct {
+	Project string
+	// Skipped is true when the project was pinned via SyncTo and so was
+	// deliberately left untouched by this Merge/syncToAll, not synced.
+	Skipped  bool
+	Duration time.Duration
+	Err      error
+}
+
 // SyncManager handles all sync operations
 type SyncManager struct {
-	mainPath string
-	registry *Registry
-	config   *utils.Config
-	appPaths utils.AppPaths
+	mainPath      string
+	registry      *Registry
+	config        *utils.Config
+	appPaths      utils.AppPaths
+	gitService    git.GitService
+	backupManager *backup.Manager
 }
 
 // NewSyncManager creates a new sync manager
@@ -70,16 +89,26 @@ func NewSyncManager() (*SyncManager, error) {
 	}
 	utils.Debug("Registry loaded successfully")
 
+	credentials := git.Credentials{
+		SSHKeyPath:      config.SSHKeyPath,
+		KnownHostsPath:  config.KnownHostsPath,
+		HTTPUserEnvVar:  config.HTTPUserEnvVar,
+		HTTPTokenEnvVar: config.HTTPTokenEnvVar,
+	}
+	gitService := git.NewGitService(credentials)
+
 	return &SyncManager{
-		mainPath: mainPath,
-		registry: registry,
-		config:   config,
-		appPaths: appPaths,
+		mainPath:      mainPath,
+		registry:      registry,
+		config:        config,
+		appPaths:      appPaths,
+		gitService:    gitService,
+		backupManager: backup.NewManager(mainPath, appPaths.DataDir, gitService),
 	}, nil
 }
 
 // Init copies rules from main location to current directory
-func (sm *SyncManager) Init() error {
+func (sm *SyncManager) Init(ctx context.Context) error {
 	currentDir, err := os.Getwd()
 	if err != nil {
 		utils.Error("Cannot get current directory | error=" + err.Error())
@@ -146,7 +175,7 @@ func (sm *SyncManager) Init() error {
 
 	// Copy from main to current
 	utils.Debug("Copying rules to current directory | source=" + sm.mainPath + ", target=" + targetPath)
-	if err := utils.CopyDir(sm.mainPath, targetPath); err != nil {
+	if err := utils.CopyDir(ctx, sm.mainPath, targetPath); err != nil {
 		utils.Error("Failed to copy rules | source=" + sm.mainPath + ", target=" + targetPath + ", error=" + err.Error())
 		return fmt.Errorf("failed to copy rules: %v", err)
 	}
@@ -169,6 +198,7 @@ func (sm *SyncManager) offerMainLocationOptions() bool {
 	options := []string{
 		"Create empty directory structure",
 		"Fetch from git repository",
+		"Force re-sync from git repository (fetch + reset, keeps .git)",
 		"Cancel operation",
 	}
 
@@ -228,58 +258,121 @@ func (sm *SyncManager) offerMainLocationOptions() bool {
 			}
 		}
 
+		gitService := sm.gitService
+		if strings.HasPrefix(gitURL, "git@") || strings.HasPrefix(gitURL, "ssh://") {
+			keyPath := ui.PromptInputWithDefault("Enter SSH key path:", defaultSSHKeyPath(), git.ValidateSSHKeyPath)
+			sm.config.SSHKeyPath = keyPath
+			credentials := git.Credentials{
+				SSHKeyPath:      keyPath,
+				KnownHostsPath:  sm.config.KnownHostsPath,
+				HTTPUserEnvVar:  sm.config.HTTPUserEnvVar,
+				HTTPTokenEnvVar: sm.config.HTTPTokenEnvVar,
+			}
+			gitService = git.NewGitService(credentials)
+			if err := sm.config.Save(); err != nil {
+				utils.Warn("Failed to persist SSH key path | error=" + err.Error())
+			}
+		}
+
 		// Verify if the repository exists
 		ui.Info("Verifying git repository...")
-		if !git.IsValidRepo(gitURL) {
-			ui.Error("Invalid git repository URL or repository not accessible")
+		ctx := context.Background()
+		if err := gitService.ValidateRemote(ctx, gitURL); err != nil {
+			ui.Error("Invalid git repository URL or repository not accessible: %v", err)
 			return false
 		}
 
 		// Clone the repository
 		ui.Info("Cloning git repository to %s...", sm.mainPath)
-		if err := git.Clone(gitURL, sm.mainPath); err != nil {
+		if err := gitService.Clone(ctx, gitURL, sm.mainPath, git.CloneOptions{}); err != nil {
 			git.CleanupOnFailure(sm.mainPath)
 			ui.Error("Failed to clone repository: %v", err)
 			return false
 		}
+		sm.gitService = gitService
 		ui.Success("Repository cloned successfully")
 		return true
 
+	case 2: // Force re-sync: fetch + hard reset instead of delete + re-clone
+		if !utils.DirExists(sm.mainPath) {
+			ui.Error("Cannot force re-sync: %s does not exist", sm.mainPath)
+			return false
+		}
+
+		ui.Info("Fetching latest changes for %s...", sm.mainPath)
+		ctx := context.Background()
+		if err := sm.gitService.Fetch(ctx, sm.mainPath, ""); err != nil {
+			utils.Error("Failed to fetch | path=" + sm.mainPath + ", error=" + err.Error())
+			ui.Error("Failed to fetch repository: %v", err)
+			return false
+		}
+
+		ui.Info("Resetting %s to origin's latest commit...", sm.mainPath)
+		if err := sm.gitService.Reset(ctx, sm.mainPath, "origin/HEAD", true); err != nil {
+			utils.Error("Failed to reset | path=" + sm.mainPath + ", error=" + err.Error())
+			ui.Error("Failed to reset repository: %v", err)
+			return false
+		}
+		ui.Success("Repository force re-synced successfully")
+		return true
+
 	default: // Cancel
 		return false
 	}
 }
 
-// Merge copies current rules to main and syncs to all locations
-func (sm *SyncManager) Merge() error {
+// defaultSSHKeyPath returns the conventional SSH private key path
+// (~/.ssh/id_ed25519), used to pre-fill the SSH key prompt in
+// offerMainLocationOptions. Falls back to a bare relative path if the user's
+// home directory can't be resolved.
+func defaultSSHKeyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".ssh", "id_ed25519")
+	}
+	return filepath.Join(home, ".ssh", "id_ed25519")
+}
+
+// Merge copies current rules to main and syncs to all locations. When
+// failFast is true, the first project sync failure cancels the context
+// passed to the workers so any still-running ones abort instead of
+// continuing to completion.
+func (sm *SyncManager) Merge(ctx context.Context, failFast bool) ([]SyncResult, error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
 		utils.Error("Cannot get current directory | error=" + err.Error())
-		return fmt.Errorf("cannot get current directory: %v", err)
+		return nil, fmt.Errorf("cannot get current directory: %v", err)
 	}
 
 	sourcePath := filepath.Join(currentDir, sm.config.RulesDirName)
 	utils.Debug("Checking for rules in current directory | path=" + sourcePath)
 	if !utils.DirExists(sourcePath) {
 		utils.Error("Rules not found in current directory | path=" + sourcePath)
-		return fmt.Errorf("%s not found in current directory", sm.config.RulesDirName)
+		return nil, fmt.Errorf("%s not found in current directory", sm.config.RulesDirName)
+	}
+
+	// Snapshot main before overwriting it, so a bad merge can be undone.
+	label := "pre-merge-" + time.Now().UTC().Format("20060102T150405Z")
+	if _, err := sm.backupManager.Create(label); err != nil {
+		utils.Error("Failed to snapshot main before merge | error=" + err.Error())
+		return nil, fmt.Errorf("failed to snapshot main before merge: %w", err)
 	}
 
 	// Copy to main
 	utils.Debug("Copying rules to main location | source=" + sourcePath + ", target=" + sm.mainPath)
-	if err := utils.CopyDir(sourcePath, sm.mainPath); err != nil {
+	if err := utils.CopyDir(ctx, sourcePath, sm.mainPath); err != nil {
 		utils.Error("Failed to copy to main | source=" + sourcePath + ", target=" + sm.mainPath + ", error=" + err.Error())
-		return fmt.Errorf("failed to copy to main: %v", err)
+		return nil, fmt.Errorf("failed to copy to main: %v", err)
 	}
 	utils.Info("Rules merged to main location | source=" + sourcePath)
 
 	// Sync to all registered projects
 	utils.Debug("Starting sync to all registered projects")
-	return sm.syncToAll()
+	return sm.syncToAll(ctx, failFast)
 }
 
 // Sync forces sync from main to current
-func (sm *SyncManager) Sync() error {
+func (sm *SyncManager) Sync(ctx context.Context) error {
 	currentDir, err := os.Getwd()
 	if err != nil {
 		utils.Error("Cannot get current directory | error=" + err.Error())
@@ -289,38 +382,60 @@ func (sm *SyncManager) Sync() error {
 	targetPath := filepath.Join(currentDir, sm.config.RulesDirName)
 	utils.Debug("Syncing rules from main location | source=" + sm.mainPath + ", target=" + targetPath)
 
-	if err := utils.CopyDir(sm.mainPath, targetPath); err != nil {
+	if err := utils.CopyDir(ctx, sm.mainPath, targetPath); err != nil {
 		utils.Error("Failed to sync rules | source=" + sm.mainPath + ", target=" + targetPath + ", error=" + err.Error())
 		return err
 	}
 
+	if err := sm.recordLock(ctx, currentDir); err != nil {
+		utils.Error("Failed to write lockfile | project=" + currentDir + ", error=" + err.Error())
+		return err
+	}
+
 	utils.Info("Rules synced successfully | target=" + targetPath)
 	return nil
 }
 
-// syncToAll syncs main rules to all registered projects
-func (sm *SyncManager) syncToAll() error {
+// syncToAll fans out main-rules copies to every registered project with
+// bounded concurrency (config.SyncConcurrency, default runtime.NumCPU()) and
+// returns a per-project result instead of printing warnings directly, so
+// callers (the CLI) can render a result table. When failFast is true, the
+// first error cancels the workers' context (derived from ctx) so in-flight
+// copies abort rather than run to completion.
+func (sm *SyncManager) syncToAll(ctx context.Context, failFast bool) ([]SyncResult, error) {
 	projects := sm.registry.GetProjects()
 	utils.Debug("Syncing to all projects | count=" + fmt.Sprintf("%d", len(projects)))
 
-	succeeded := 0
-	failed := 0
+	concurrency := sm.config.SyncConcurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
 
-	for _, project := range projects {
-		// Check if project directory exists
-		if !utils.DirExists(project) {
-			utils.Warn("Project directory does not exist | project=" + project)
-			fmt.Printf("Warning: skipping non-existent project: %s\n", project)
-			failed++
-			continue
-		}
+	results := make([]SyncResult, len(projects))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+
+	for i, entry := range projects {
+		i, entry := i, entry
+		g.Go(func() error {
+			if entry.PinnedRef != "" {
+				utils.Debug("Skipping pinned project | project=" + entry.Path + ", pinnedRef=" + entry.PinnedRef)
+				results[i] = SyncResult{Project: entry.Path, Skipped: true}
+				return nil
+			}
+			results[i] = sm.syncProject(gctx, entry.Path)
+			if results[i].Err != nil && failFast {
+				return results[i].Err
+			}
+			return nil
+		})
+	}
 
-		targetPath := filepath.Join(project, sm.config.RulesDirName)
-		utils.Debug("Syncing to project | project=" + project + ", target=" + targetPath)
+	groupErr := g.Wait()
 
-		if err := utils.CopyDir(sm.mainPath, targetPath); err != nil {
-			utils.Warn("Failed to sync to project | project=" + project + ", error=" + err.Error())
-			fmt.Printf("Warning: failed to sync to %s: %v\n", project, err)
+	succeeded, failed := 0, 0
+	for _, r := range results {
+		if r.Err != nil {
 			failed++
 		} else {
 			succeeded++
@@ -328,7 +443,32 @@ func (sm *SyncManager) syncToAll() error {
 	}
 
 	utils.Info("Sync to all projects completed | successful=" + fmt.Sprintf("%d", succeeded) + ", failed=" + fmt.Sprintf("%d", failed))
-	return nil
+	return results, groupErr
+}
+
+// syncProject copies main rules into a single project, timing the attempt.
+func (sm *SyncManager) syncProject(ctx context.Context, project string) SyncResult {
+	start := time.Now()
+
+	if !utils.DirExists(project) {
+		utils.Warn("Project directory does not exist | project=" + project)
+		return SyncResult{Project: project, Duration: time.Since(start), Err: fmt.Errorf("project directory does not exist: %s", project)}
+	}
+
+	targetPath := filepath.Join(project, sm.config.RulesDirName)
+	utils.Debug("Syncing to project | project=" + project + ", target=" + targetPath)
+
+	if err := utils.CopyDir(ctx, sm.mainPath, targetPath); err != nil {
+		utils.Warn("Failed to sync to project | project=" + project + ", error=" + err.Error())
+		return SyncResult{Project: project, Duration: time.Since(start), Err: err}
+	}
+
+	if err := sm.recordLock(ctx, project); err != nil {
+		utils.Warn("Failed to write lockfile | project=" + project + ", error=" + err.Error())
+		return SyncResult{Project: project, Duration: time.Since(start), Err: err}
+	}
+
+	return SyncResult{Project: project, Duration: time.Since(start)}
 }
 
 // GetRegistry returns the registry instance
@@ -340,3 +480,120 @@ func (sm *SyncManager) GetRegistry() *Registry {
 func (sm *SyncManager) Clean() (int, error) {
 	return sm.registry.CleanProjects()
 }
+
+// SyncTo pins project to ref: it checks out ref in main, copies main's rules
+// into project, writes a lockfile recording the pin, then restores main to
+// its prior branch (falling back to its prior commit SHA if main was already
+// detached) so other projects keep syncing from the latest commit on the
+// right branch instead of a detached HEAD.
+func (sm *SyncManager) SyncTo(ctx context.Context, project, ref string) error {
+	priorHead, err := sm.gitService.Id(ctx, sm.mainPath)
+	if err != nil {
+		utils.Error("Failed to read main HEAD | path=" + sm.mainPath + ", error=" + err.Error())
+		return fmt.Errorf("failed to read main HEAD: %w", err)
+	}
+
+	priorBranch, err := sm.gitService.Branch(ctx, sm.mainPath)
+	if err != nil {
+		utils.Error("Failed to read main branch | path=" + sm.mainPath + ", error=" + err.Error())
+		return fmt.Errorf("failed to read main branch: %w", err)
+	}
+
+	priorRef := priorHead
+	if priorBranch != "" && priorBranch != "HEAD" {
+		priorRef = priorBranch
+	}
+
+	utils.Debug("Checking out pinned ref | ref=" + ref + ", path=" + sm.mainPath)
+	if err := sm.gitService.Checkout(ctx, sm.mainPath, ref); err != nil {
+		utils.Error("Failed to checkout ref | ref=" + ref + ", error=" + err.Error())
+		return fmt.Errorf("failed to checkout %s: %w", ref, err)
+	}
+
+	targetPath := filepath.Join(project, sm.config.RulesDirName)
+	copyErr := utils.CopyDir(ctx, sm.mainPath, targetPath)
+	lockErr := error(nil)
+	if copyErr == nil {
+		lockErr = sm.recordLock(ctx, project)
+	}
+	if lockErr == nil && copyErr == nil {
+		lockErr = sm.registry.SetPinnedRef(project, ref)
+	}
+
+	utils.Debug("Restoring main HEAD | ref=" + priorRef + ", path=" + sm.mainPath)
+	if err := sm.gitService.Checkout(ctx, sm.mainPath, priorRef); err != nil {
+		utils.Error("Failed to restore main HEAD | ref=" + priorRef + ", error=" + err.Error())
+		return fmt.Errorf("failed to restore main HEAD to %s: %w", priorRef, err)
+	}
+
+	if copyErr != nil {
+		return fmt.Errorf("failed to copy pinned rules to %s: %w", project, copyErr)
+	}
+	if lockErr != nil {
+		return fmt.Errorf("failed to record pin for %s: %w", project, lockErr)
+	}
+
+	utils.Info("Project pinned to ref | project=" + project + ", ref=" + ref)
+	return nil
+}
+
+// VerifyResult reports whether a registered project's lockfile SHA still
+// matches the commit it's expected to track: main's current commit for an
+// unpinned project, or its PinnedRef's resolved commit for a pinned one.
+type VerifyResult struct {
+	Project string
+	Pinned  bool
+	Drifted bool
+	Err     error
+}
+
+// Verify walks every registered project and reports whether its lockfile SHA
+// has drifted from the commit it's expected to track. Pinned projects are
+// compared against their PinnedRef (not main's current commit), so an
+// intentional pin is never reported as drift just because main moved on.
+func (sm *SyncManager) Verify(ctx context.Context) ([]VerifyResult, error) {
+	mainSHA, err := sm.gitService.Id(ctx, sm.mainPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read main HEAD: %w", err)
+	}
+
+	projects := sm.registry.GetProjects()
+	results := make([]VerifyResult, 0, len(projects))
+
+	for _, entry := range projects {
+		lock, err := readLock(entry.Path)
+		if err != nil {
+			results = append(results, VerifyResult{Project: entry.Path, Pinned: entry.PinnedRef != "", Err: err})
+			continue
+		}
+
+		expectedSHA := mainSHA
+		if entry.PinnedRef != "" {
+			expectedSHA, err = sm.gitService.ResolveRef(ctx, sm.mainPath, entry.PinnedRef)
+			if err != nil {
+				results = append(results, VerifyResult{Project: entry.Path, Pinned: true, Err: fmt.Errorf("failed to resolve pinned ref %s: %w", entry.PinnedRef, err)})
+				continue
+			}
+		}
+
+		results = append(results, VerifyResult{Project: entry.Path, Pinned: entry.PinnedRef != "", Drifted: lock.SHA != expectedSHA})
+	}
+
+	return results, nil
+}
+
+// SnapshotCreate takes an on-demand snapshot of the main rules directory,
+// labeled label, independent of Merge's automatic pre-merge snapshot.
+func (sm *SyncManager) SnapshotCreate(label string) (backup.SnapshotID, error) {
+	return sm.backupManager.Create(label)
+}
+
+// Snapshots lists every snapshot taken of the main rules directory.
+func (sm *SyncManager) Snapshots() ([]backup.Snapshot, error) {
+	return sm.backupManager.List()
+}
+
+// SnapshotRestore restores the main rules directory to snapshot id.
+func (sm *SyncManager) SnapshotRestore(id backup.SnapshotID) error {
+	return sm.backupManager.Restore(id)
+}
@@ -0,0 +1,160 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// These tests exercise the GitService returned by NewGitService against a
+// real on-disk repository, so they pass identically against whichever
+// backend the build tag selects — run as `go test ./internal/git/...` for
+// GitCommandService and `go test -tags gitgo ./internal/git/...` for
+// GitGoService.
+
+// runGit runs git in dir for test setup, failing the test on error.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out.String())
+	}
+	return strings.TrimSpace(out.String())
+}
+
+// newConformanceRepo creates a temp repo with a first commit on main and a
+// second commit on a "feature" branch, returning the repo path and both
+// commits' SHAs.
+func newConformanceRepo(t *testing.T) (repoPath, mainSHA, featureSHA string) {
+	t.Helper()
+	repoPath = t.TempDir()
+
+	runGit(t, repoPath, "init", "-q", "-b", "main")
+	runGit(t, repoPath, "config", "user.email", "test@example.com")
+	runGit(t, repoPath, "config", "user.name", "Test")
+	runGit(t, repoPath, "commit", "--allow-empty", "-q", "-m", "initial")
+	mainSHA = runGit(t, repoPath, "rev-parse", "HEAD")
+
+	runGit(t, repoPath, "checkout", "-q", "-b", "feature")
+	runGit(t, repoPath, "commit", "--allow-empty", "-q", "-m", "feature work")
+	featureSHA = runGit(t, repoPath, "rev-parse", "HEAD")
+
+	runGit(t, repoPath, "checkout", "-q", "main")
+	return repoPath, mainSHA, featureSHA
+}
+
+func TestConformance_IdAndBranch(t *testing.T) {
+	repoPath, mainSHA, _ := newConformanceRepo(t)
+	svc := NewGitService(Credentials{})
+	ctx := context.Background()
+
+	id, err := svc.Id(ctx, repoPath)
+	if err != nil {
+		t.Fatalf("Id() error = %v", err)
+	}
+	if id != mainSHA {
+		t.Errorf("Id() = %q, want %q", id, mainSHA)
+	}
+
+	branch, err := svc.Branch(ctx, repoPath)
+	if err != nil {
+		t.Fatalf("Branch() error = %v", err)
+	}
+	if branch != "main" {
+		t.Errorf("Branch() = %q, want %q", branch, "main")
+	}
+}
+
+func TestConformance_Checkout(t *testing.T) {
+	repoPath, _, featureSHA := newConformanceRepo(t)
+	svc := NewGitService(Credentials{})
+	ctx := context.Background()
+
+	if err := svc.Checkout(ctx, repoPath, "feature"); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	id, err := svc.Id(ctx, repoPath)
+	if err != nil {
+		t.Fatalf("Id() error = %v", err)
+	}
+	if id != featureSHA {
+		t.Errorf("Id() after Checkout(feature) = %q, want %q", id, featureSHA)
+	}
+
+	branch, err := svc.Branch(ctx, repoPath)
+	if err != nil {
+		t.Fatalf("Branch() error = %v", err)
+	}
+	if branch != "feature" {
+		t.Errorf("Branch() after Checkout(feature) = %q, want %q", branch, "feature")
+	}
+}
+
+func TestConformance_ResolveRef(t *testing.T) {
+	repoPath, mainSHA, featureSHA := newConformanceRepo(t)
+	svc := NewGitService(Credentials{})
+	ctx := context.Background()
+
+	gotMain, err := svc.ResolveRef(ctx, repoPath, "main")
+	if err != nil {
+		t.Fatalf("ResolveRef(main) error = %v", err)
+	}
+	if gotMain != mainSHA {
+		t.Errorf("ResolveRef(main) = %q, want %q", gotMain, mainSHA)
+	}
+
+	gotFeature, err := svc.ResolveRef(ctx, repoPath, "feature")
+	if err != nil {
+		t.Fatalf("ResolveRef(feature) error = %v", err)
+	}
+	if gotFeature != featureSHA {
+		t.Errorf("ResolveRef(feature) = %q, want %q", gotFeature, featureSHA)
+	}
+}
+
+func TestConformance_Reset(t *testing.T) {
+	repoPath, mainSHA, featureSHA := newConformanceRepo(t)
+	svc := NewGitService(Credentials{})
+	ctx := context.Background()
+
+	if err := svc.Checkout(ctx, repoPath, "feature"); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if id, _ := svc.Id(ctx, repoPath); id != featureSHA {
+		t.Fatalf("precondition: expected to be on featureSHA, got %q", id)
+	}
+
+	if err := svc.Reset(ctx, repoPath, mainSHA, false); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+
+	id, err := svc.Id(ctx, repoPath)
+	if err != nil {
+		t.Fatalf("Id() error = %v", err)
+	}
+	if id != mainSHA {
+		t.Errorf("Id() after Reset(mainSHA) = %q, want %q", id, mainSHA)
+	}
+}
+
+func TestConformance_CleanupOnFailure(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "partial-clone")
+	if err := os.Mkdir(dest, 0755); err != nil {
+		t.Fatalf("setup mkdir failed: %v", err)
+	}
+
+	CleanupOnFailure(dest)
+
+	if checkIfCleanupIsNeeded(dest) {
+		t.Errorf("CleanupOnFailure() left %s behind", dest)
+	}
+}
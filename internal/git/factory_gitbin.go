@@ -0,0 +1,10 @@
+//go:build !gitgo
+
+package git
+
+// NewGitService creates the GitService backend selected by the gitgo build
+// tag. Without the tag (the default), that's GitCommandService, shelling out
+// to the git binary on PATH.
+func NewGitService(creds Credentials) GitService {
+	return NewGitCommandService(nil, creds)
+}
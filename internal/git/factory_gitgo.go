@@ -0,0 +1,10 @@
+//go:build gitgo
+
+package git
+
+// NewGitService creates the GitService backend selected by the gitgo build
+// tag. With the tag set, that's GitGoService, pure-Go via go-git, requiring
+// no git binary on PATH.
+func NewGitService(creds Credentials) GitService {
+	return NewGitGoService(creds)
+}
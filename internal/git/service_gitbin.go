@@ -0,0 +1,165 @@
+//go:build !gitgo
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GitCommandService implements GitService by shelling out to the git binary.
+type GitCommandService struct {
+	executor    CommandExecutor
+	credentials Credentials
+}
+
+// NewGitCommandService creates a new GitCommandService. creds configures how
+// outbound network operations (Clone, Fetch, Pull, ValidateRemote) authenticate.
+func NewGitCommandService(executor CommandExecutor, creds Credentials) GitService {
+	if executor == nil {
+		executor = &ShellCommandExecutor{}
+	}
+	return &GitCommandService{executor: executor, credentials: creds}
+}
+
+// execAuthed runs a git command against url, applying SSH/HTTP credentials.
+func (s *GitCommandService) execAuthed(ctx context.Context, url string, args ...string) ([]byte, error) {
+	env, argvPrefix, err := authForURL(s.credentials, url)
+	if err != nil {
+		return nil, err
+	}
+	fullArgs := append(append([]string{}, argvPrefix...), args...)
+	return s.executor.ExecuteWithEnv(ctx, env, "git", fullArgs...)
+}
+
+// Clone clones a git repository, applying the given options to the clone argv.
+func (s *GitCommandService) Clone(ctx context.Context, url, dest string, opts CloneOptions) error {
+	args := []string{"clone"}
+	if opts.Recursive {
+		args = append(args, "--recursive")
+	}
+	if opts.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(opts.Depth))
+	}
+	if opts.ShallowSubmodules {
+		args = append(args, "--shallow-submodules")
+	}
+	if opts.SingleBranch {
+		args = append(args, "--single-branch")
+	}
+	if opts.Branch != "" {
+		args = append(args, "-b", opts.Branch)
+	}
+	args = append(args, url, dest)
+
+	output, err := s.execAuthed(ctx, url, args...)
+	if err != nil {
+		CleanupOnFailure(dest)
+		return fmt.Errorf("git clone failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// Pull updates a git repository
+func (s *GitCommandService) Pull(ctx context.Context, repoPath string) error {
+	url, err := s.remoteURL(ctx, repoPath)
+	if err != nil {
+		return fmt.Errorf("git pull failed: %w", err)
+	}
+
+	output, err := s.execAuthed(ctx, url, "-C", repoPath, "pull")
+	if err != nil {
+		return fmt.Errorf("git pull failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// remoteURL returns repoPath's "origin" remote URL.
+func (s *GitCommandService) remoteURL(ctx context.Context, repoPath string) (string, error) {
+	output, err := s.executor.Execute(ctx, "git", "-C", repoPath, "remote", "get-url", "origin")
+	if err != nil {
+		return "", fmt.Errorf("failed to read origin remote: %w\nOutput: %s", err, output)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ValidateRemote checks that url is reachable and authenticates, without cloning it.
+func (s *GitCommandService) ValidateRemote(ctx context.Context, url string) error {
+	output, err := s.execAuthed(ctx, url, "ls-remote", "--exit-code", url)
+	if err != nil {
+		return fmt.Errorf("git ls-remote failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// Checkout checks out a specific reference in a git repository
+func (s *GitCommandService) Checkout(ctx context.Context, repoPath, ref string) error {
+	output, err := s.executor.Execute(ctx, "git", "-C", repoPath, "checkout", ref)
+	if err != nil {
+		return fmt.Errorf("git checkout failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// Fetch updates repoPath's remote-tracking refs for refspec without touching the working tree.
+func (s *GitCommandService) Fetch(ctx context.Context, repoPath, refspec string) error {
+	url, err := s.remoteURL(ctx, repoPath)
+	if err != nil {
+		return fmt.Errorf("git fetch failed: %w", err)
+	}
+
+	args := []string{"-C", repoPath, "fetch", "origin"}
+	if refspec != "" {
+		args = append(args, refspec)
+	}
+	output, err := s.execAuthed(ctx, url, args...)
+	if err != nil {
+		return fmt.Errorf("git fetch failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// Reset hard-resets repoPath to ref, bringing a dirty checkout back to a known state
+// without risking merge conflicts.
+func (s *GitCommandService) Reset(ctx context.Context, repoPath, ref string, recurseSubmodules bool) error {
+	args := []string{"-C", repoPath, "reset", "--hard"}
+	if recurseSubmodules {
+		args = append(args, "--recurse-submodules=on-demand")
+	}
+	args = append(args, ref)
+
+	output, err := s.executor.Execute(ctx, "git", args...)
+	if err != nil {
+		return fmt.Errorf("git reset failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// Id returns the current commit SHA checked out at repoPath.
+func (s *GitCommandService) Id(ctx context.Context, repoPath string) (string, error) {
+	output, err := s.executor.Execute(ctx, "git", "-C", repoPath, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w\nOutput: %s", err, output)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Branch returns the current branch name checked out at repoPath.
+func (s *GitCommandService) Branch(ctx context.Context, repoPath string) (string, error) {
+	output, err := s.executor.Execute(ctx, "git", "-C", repoPath, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse --abbrev-ref failed: %w\nOutput: %s", err, output)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ResolveRef resolves ref to its commit SHA in repoPath, without checking it out.
+func (s *GitCommandService) ResolveRef(ctx context.Context, repoPath, ref string) (string, error) {
+	output, err := s.executor.Execute(ctx, "git", "-C", repoPath, "rev-parse", ref)
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w\nOutput: %s", err, output)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,158 @@
+//go:build !gitgo
+
+package git
+
+import (
+	"context"
+	"testing"
+)
+
+// fakeExecutor is a CommandExecutor test double that records the argv of
+// every invocation instead of running anything.
+type fakeExecutor struct {
+	calls  [][]string
+	output []byte
+	err    error
+}
+
+func (f *fakeExecutor) Execute(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return f.ExecuteWithEnv(ctx, nil, name, args...)
+}
+
+func (f *fakeExecutor) ExecuteWithEnv(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+	call := append([]string{name}, args...)
+	f.calls = append(f.calls, call)
+	return f.output, f.err
+}
+
+func TestGitCommandService_Clone_Argv(t *testing.T) {
+	tests := []struct {
+		name string
+		opts CloneOptions
+		want []string
+	}{
+		{
+			name: "no options",
+			opts: CloneOptions{},
+			want: []string{"git", "clone", "https://example.com/repo.git", "/dest"},
+		},
+		{
+			name: "recursive",
+			opts: CloneOptions{Recursive: true},
+			want: []string{"git", "clone", "--recursive", "https://example.com/repo.git", "/dest"},
+		},
+		{
+			name: "depth",
+			opts: CloneOptions{Depth: 1},
+			want: []string{"git", "clone", "--depth", "1", "https://example.com/repo.git", "/dest"},
+		},
+		{
+			name: "shallow submodules",
+			opts: CloneOptions{Recursive: true, Depth: 1, ShallowSubmodules: true},
+			want: []string{"git", "clone", "--recursive", "--depth", "1", "--shallow-submodules", "https://example.com/repo.git", "/dest"},
+		},
+		{
+			name: "single branch with branch name",
+			opts: CloneOptions{SingleBranch: true, Branch: "main"},
+			want: []string{"git", "clone", "--single-branch", "-b", "main", "https://example.com/repo.git", "/dest"},
+		},
+		{
+			name: "every option combined",
+			opts: CloneOptions{Recursive: true, Depth: 2, ShallowSubmodules: true, SingleBranch: true, Branch: "release"},
+			want: []string{"git", "clone", "--recursive", "--depth", "2", "--shallow-submodules", "--single-branch", "-b", "release", "https://example.com/repo.git", "/dest"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &fakeExecutor{}
+			svc := NewGitCommandService(exec, Credentials{})
+
+			if err := svc.Clone(context.Background(), "https://example.com/repo.git", "/dest", tt.opts); err != nil {
+				t.Fatalf("Clone() error = %v", err)
+			}
+
+			if len(exec.calls) != 1 {
+				t.Fatalf("got %d exec calls, want 1: %v", len(exec.calls), exec.calls)
+			}
+			assertArgv(t, exec.calls[0], tt.want)
+		})
+	}
+}
+
+func TestGitCommandService_Reset_Argv(t *testing.T) {
+	tests := []struct {
+		name              string
+		ref               string
+		recurseSubmodules bool
+		want              []string
+	}{
+		{
+			name: "without submodules",
+			ref:  "origin/HEAD",
+			want: []string{"git", "-C", "/repo", "reset", "--hard", "origin/HEAD"},
+		},
+		{
+			name:              "with submodules",
+			ref:               "abc123",
+			recurseSubmodules: true,
+			want:              []string{"git", "-C", "/repo", "reset", "--hard", "--recurse-submodules=on-demand", "abc123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &fakeExecutor{}
+			svc := NewGitCommandService(exec, Credentials{})
+
+			if err := svc.Reset(context.Background(), "/repo", tt.ref, tt.recurseSubmodules); err != nil {
+				t.Fatalf("Reset() error = %v", err)
+			}
+
+			if len(exec.calls) != 1 {
+				t.Fatalf("got %d exec calls, want 1: %v", len(exec.calls), exec.calls)
+			}
+			assertArgv(t, exec.calls[0], tt.want)
+		})
+	}
+}
+
+func TestGitCommandService_Fetch_Argv(t *testing.T) {
+	exec := &fakeExecutor{output: []byte("https://example.com/repo.git")}
+	svc := NewGitCommandService(exec, Credentials{})
+
+	if err := svc.Fetch(context.Background(), "/repo", "refs/heads/main"); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	if len(exec.calls) != 2 {
+		t.Fatalf("got %d exec calls, want 2 (remote lookup + fetch): %v", len(exec.calls), exec.calls)
+	}
+	assertArgv(t, exec.calls[1], []string{"git", "-C", "/repo", "fetch", "origin", "refs/heads/main"})
+}
+
+func TestGitCommandService_Id_Argv(t *testing.T) {
+	exec := &fakeExecutor{output: []byte("deadbeef\n")}
+	svc := NewGitCommandService(exec, Credentials{})
+
+	sha, err := svc.Id(context.Background(), "/repo")
+	if err != nil {
+		t.Fatalf("Id() error = %v", err)
+	}
+	if sha != "deadbeef" {
+		t.Errorf("Id() = %q, want %q", sha, "deadbeef")
+	}
+	assertArgv(t, exec.calls[0], []string{"git", "-C", "/repo", "rev-parse", "HEAD"})
+}
+
+func assertArgv(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("argv = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("argv = %v, want %v", got, want)
+		}
+	}
+}
